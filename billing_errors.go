@@ -0,0 +1,112 @@
+package recurly
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TransactionError describes why a payment gateway rejected a
+// transaction, as parsed from Recurly's <transaction_error> XML element.
+type TransactionError struct {
+	ErrorCode        string `xml:"error_code"`
+	ErrorCategory    string `xml:"error_category"`
+	MerchantMessage  string `xml:"merchant_message"`
+	CustomerMessage  string `xml:"customer_message"`
+	GatewayErrorCode string `xml:"gateway_error_code"`
+}
+
+// APIError is returned by billing methods for any response with a status
+// of 400 or above instead of a silently nil error. StatusCode is always
+// set; the remaining fields are populated from whatever Recurly included
+// in the response body. ClearContext does not decode the body, so its
+// APIError carries only StatusCode.
+type APIError struct {
+	StatusCode       int
+	Symbol           string
+	Description      string
+	Field            string
+	TransactionError *TransactionError
+}
+
+func (e *APIError) Error() string {
+	if e.TransactionError != nil {
+		return fmt.Sprintf("recurly: %d %s: %s", e.StatusCode, e.Symbol, e.TransactionError.CustomerMessage)
+	}
+	if e.Field != "" {
+		return fmt.Sprintf("recurly: %d %s: %s (field %s)", e.StatusCode, e.Symbol, e.Description, e.Field)
+	}
+	return fmt.Sprintf("recurly: %d %s: %s", e.StatusCode, e.Symbol, e.Description)
+}
+
+// IsDeclined reports whether err represents a gateway decline.
+func IsDeclined(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Symbol == "declined" || apiErr.TransactionError != nil
+}
+
+// IsFraudSuspected reports whether err represents a transaction rejected
+// on suspicion of fraud.
+func IsFraudSuspected(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Symbol == "fraud_suspected" ||
+		(apiErr.TransactionError != nil && apiErr.TransactionError.ErrorCategory == "fraud")
+}
+
+// IsInvalidData reports whether err represents a validation failure,
+// e.g. a malformed card number or a missing required field.
+func IsInvalidData(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == 422 && apiErr.TransactionError == nil
+}
+
+// billingResult decodes both a successful billing_info response and the
+// error body Recurly returns on failure into a single destination, since
+// the two share no overlapping field names.
+type billingResult struct {
+	Billing
+	Symbol           string            `xml:"symbol"`
+	Description      string            `xml:"description"`
+	Field            string            `xml:"field"`
+	TransactionError *TransactionError `xml:"transaction_error"`
+}
+
+func (r *billingResult) apiError(statusCode int) *APIError {
+	return &APIError{
+		StatusCode:       statusCode,
+		Symbol:           r.Symbol,
+		Description:      r.Description,
+		Field:            r.Field,
+		TransactionError: r.TransactionError,
+	}
+}
+
+// transactionResult decodes both a successful transaction response and
+// the error body Recurly returns on failure, for endpoints (verify,
+// collect) that return a transaction rather than billing info. Not every
+// failure is a gateway decline with a transaction_error element -- a
+// plain validation error (e.g. a malformed invoice number) instead sets
+// the top-level symbol/description Recurly uses everywhere else.
+type transactionResult struct {
+	Transaction
+	Symbol           string            `xml:"symbol"`
+	Description      string            `xml:"description"`
+	TransactionError *TransactionError `xml:"transaction_error"`
+}
+
+func (r *transactionResult) apiError(statusCode int) *APIError {
+	return &APIError{
+		StatusCode:       statusCode,
+		Symbol:           r.Symbol,
+		Description:      r.Description,
+		TransactionError: r.TransactionError,
+	}
+}
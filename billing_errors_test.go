@@ -0,0 +1,88 @@
+package recurly
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsDeclined(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"non-APIError", errors.New("boom"), false},
+		{"plain validation error", &APIError{StatusCode: 422, Symbol: "invalid_data"}, false},
+		{"declined symbol", &APIError{StatusCode: 402, Symbol: "declined"}, true},
+		{"has transaction error", &APIError{StatusCode: 402, TransactionError: &TransactionError{ErrorCode: "call_issuer"}}, true},
+		{"wrapped declined", fmt.Errorf("collect: %w", &APIError{StatusCode: 402, Symbol: "declined"}), true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := IsDeclined(c.err); got != c.want {
+				t.Errorf("IsDeclined(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsFraudSuspected(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"non-APIError", errors.New("boom"), false},
+		{"declined but not fraud", &APIError{StatusCode: 402, Symbol: "declined"}, false},
+		{"fraud symbol", &APIError{StatusCode: 402, Symbol: "fraud_suspected"}, true},
+		{"fraud category", &APIError{StatusCode: 402, TransactionError: &TransactionError{ErrorCategory: "fraud"}}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := IsFraudSuspected(c.err); got != c.want {
+				t.Errorf("IsFraudSuspected(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsInvalidData(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"non-APIError", errors.New("boom"), false},
+		{"422 validation error", &APIError{StatusCode: 422, Symbol: "invalid_data"}, true},
+		{"422 with transaction error is a decline, not invalid data", &APIError{StatusCode: 422, TransactionError: &TransactionError{ErrorCode: "call_issuer"}}, false},
+		{"404 not found", &APIError{StatusCode: 404, Symbol: "not_found"}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := IsInvalidData(c.err); got != c.want {
+				t.Errorf("IsInvalidData(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestTransactionResultApiErrorPopulatesSymbolAndDescription(t *testing.T) {
+	var r transactionResult
+	r.Symbol = "invalid_invoice_number"
+	r.Description = "The invoice could not be found."
+
+	err := r.apiError(404)
+	if err.Symbol != "invalid_invoice_number" || err.Description != "The invoice could not be found." {
+		t.Fatalf("apiError did not carry over Symbol/Description: %+v", err)
+	}
+	if err.Error() == "recurly: 404 : " {
+		t.Fatalf("Error() dropped the validation details: %q", err.Error())
+	}
+}
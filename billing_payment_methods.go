@@ -0,0 +1,59 @@
+package recurly
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// PaymentMethod identifies which kind of payment instrument is active on
+// an account's billing info.
+type PaymentMethod string
+
+const (
+	PaymentMethodCard        PaymentMethod = "card"
+	PaymentMethodBankAccount PaymentMethod = "bank_account"
+	PaymentMethodPayPal      PaymentMethod = "paypal"
+	PaymentMethodApplePay    PaymentMethod = "apple_pay"
+	PaymentMethodAmazon      PaymentMethod = "amazon"
+	PaymentMethodSEPA        PaymentMethod = "sepa"
+	PaymentMethodBACS        PaymentMethod = "bacs"
+)
+
+// SEPAMandate holds the IBAN and mandate details backing a SEPA direct
+// debit payment method.
+type SEPAMandate struct {
+	IBAN             string `xml:"iban,omitempty"`
+	MandateReference string `xml:"mandate_reference,omitempty"`
+	SignatureDate    string `xml:"signature_date,omitempty"` // YYYY-MM-DD
+}
+
+// BACSMandate holds the sort code and mandate details backing a BACS
+// direct debit payment method.
+type BACSMandate struct {
+	SortCode         string `xml:"sort_code,omitempty"`
+	AccountNumber    string `xml:"account_number,omitempty"`
+	MandateReference string `xml:"mandate_reference,omitempty"`
+}
+
+// SetPaymentMethod switches an account's active payment method to one
+// that's already been established on the account (e.g. via Update or a
+// PayPal/Amazon billing agreement callback). It PUTs a billing_info body
+// with only payment_method set; no method-specific fields are sent.
+// https://dev.recurly.com/docs/update-an-accounts-billing-info-credit-card
+func (s *billingImpl) SetPaymentMethod(accountCode string, method PaymentMethod) (*Response, *Billing, error) {
+	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
+	req, err := s.client.newRequest("PUT", action, nil, Billing{PaymentMethod: method})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst billingResult
+	resp, err := s.client.do(req, &dst)
+	if err != nil {
+		return resp, nil, err
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, dst.apiError(resp.StatusCode)
+	}
+
+	return resp, &dst.Billing, nil
+}
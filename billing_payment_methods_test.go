@@ -0,0 +1,143 @@
+package recurly
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// billingPaymentMethodRoundTrip marshals b as Recurly's billing_info XML
+// payload and unmarshals the result back into a Billing, the same path
+// Update's clean-copy logic and the client's do() take on the wire. The
+// returned XML is handed back too so callers can assert on the actual
+// wire elements -- round-tripping through the same struct alone would
+// stay green even if its xml tags were wrong, since Marshal/Unmarshal
+// would simply agree with each other.
+func billingPaymentMethodRoundTrip(t *testing.T, b Billing) (Billing, string) {
+	t.Helper()
+
+	data, err := xml.Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	var got Billing
+	if err := xml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	return got, string(data)
+}
+
+func assertContainsElement(t *testing.T, xmlStr, tag, value string) {
+	t.Helper()
+	want := "<" + tag + ">" + value + "</" + tag + ">"
+	if !strings.Contains(xmlStr, want) {
+		t.Errorf("expected XML to contain %s, got: %s", want, xmlStr)
+	}
+}
+
+func TestBillingCardRoundTrip(t *testing.T) {
+	want := Billing{
+		PaymentMethod:     PaymentMethodCard,
+		FirstName:         "Verena",
+		LastName:          "Example",
+		Number:            "4111111111111111",
+		Month:             11,
+		Year:              2030,
+		VerificationValue: "123",
+	}
+
+	got, data := billingPaymentMethodRoundTrip(t, want)
+	if got.PaymentMethod != want.PaymentMethod || got.Number != want.Number ||
+		got.Month != want.Month || got.Year != want.Year || got.VerificationValue != want.VerificationValue {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	assertContainsElement(t, data, "payment_method", string(PaymentMethodCard))
+	assertContainsElement(t, data, "first_name", "Verena")
+	assertContainsElement(t, data, "number", "4111111111111111")
+}
+
+func TestBillingBankAccountRoundTrip(t *testing.T) {
+	want := Billing{
+		PaymentMethod: PaymentMethodBankAccount,
+		NameOnAccount: "Verena Example",
+		RoutingNumber: "123456789",
+		AccountNumber: "987654321",
+		AccountType:   "checking",
+	}
+
+	got, data := billingPaymentMethodRoundTrip(t, want)
+	if got.PaymentMethod != want.PaymentMethod || got.NameOnAccount != want.NameOnAccount ||
+		got.RoutingNumber != want.RoutingNumber || got.AccountNumber != want.AccountNumber ||
+		got.AccountType != want.AccountType {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	assertContainsElement(t, data, "routing_number", "123456789")
+	assertContainsElement(t, data, "account_number", "987654321")
+	assertContainsElement(t, data, "account_type", "checking")
+}
+
+func TestBillingSEPARoundTrip(t *testing.T) {
+	want := Billing{
+		PaymentMethod: PaymentMethodSEPA,
+		SEPAMandate: SEPAMandate{
+			IBAN:             "DE89370400440532013000",
+			MandateReference: "MANDATE-123",
+			SignatureDate:    "2020-01-15",
+		},
+	}
+
+	got, data := billingPaymentMethodRoundTrip(t, want)
+	if got.PaymentMethod != want.PaymentMethod || got.SEPAMandate != want.SEPAMandate {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	assertContainsElement(t, data, "iban", "DE89370400440532013000")
+	assertContainsElement(t, data, "mandate_reference", "MANDATE-123")
+	assertContainsElement(t, data, "signature_date", "2020-01-15")
+}
+
+func TestBillingBACSRoundTrip(t *testing.T) {
+	want := Billing{
+		PaymentMethod: PaymentMethodBACS,
+		BACSMandate: BACSMandate{
+			SortCode:         "12-34-56",
+			AccountNumber:    "12345678",
+			MandateReference: "MANDATE-456",
+		},
+	}
+
+	got, data := billingPaymentMethodRoundTrip(t, want)
+	if got.PaymentMethod != want.PaymentMethod || got.BACSMandate != want.BACSMandate {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	assertContainsElement(t, data, "sort_code", "12-34-56")
+	assertContainsElement(t, data, "account_number", "12345678")
+	assertContainsElement(t, data, "mandate_reference", "MANDATE-456")
+}
+
+func TestBillingPayPalRoundTrip(t *testing.T) {
+	want := Billing{
+		PaymentMethod:            PaymentMethodPayPal,
+		PayPalBillingAgreementID: "B-AGREEMENT-1",
+	}
+
+	got, data := billingPaymentMethodRoundTrip(t, want)
+	if got.PaymentMethod != want.PaymentMethod || got.PayPalBillingAgreementID != want.PayPalBillingAgreementID {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	assertContainsElement(t, data, "billing_agreement_id", "B-AGREEMENT-1")
+}
+
+func TestBillingAmazonRoundTrip(t *testing.T) {
+	want := Billing{
+		PaymentMethod:            PaymentMethodAmazon,
+		AmazonBillingAgreementID: "P-AGREEMENT-1",
+	}
+
+	got, data := billingPaymentMethodRoundTrip(t, want)
+	if got.PaymentMethod != want.PaymentMethod || got.AmazonBillingAgreementID != want.AmazonBillingAgreementID {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	assertContainsElement(t, data, "billing_agreement_id", "P-AGREEMENT-1")
+}
@@ -0,0 +1,41 @@
+package recurly
+
+import (
+	"context"
+	"fmt"
+)
+
+// FuncTokenProvider adapts a function to TokenProvider, letting callers
+// wire up their own PSP-specific exchange (Braintree, Stripe, Adyen, or
+// anything else) without this package needing to hardcode each PSP's
+// wire format and auth scheme.
+type FuncTokenProvider func(ctx context.Context, card RawCard) (string, error)
+
+// Tokenize calls f.
+func (f FuncTokenProvider) Tokenize(ctx context.Context, card RawCard) (string, error) {
+	return f(ctx, card)
+}
+
+// RecurlyJSTokenProvider adapts a token already produced client-side by
+// recurly.js to TokenProvider, so CreateWithProvider/UpdateWithProvider
+// can be used uniformly regardless of which tokenization path an
+// application takes. It never sees a RawCard; recurly.js never exposes
+// one to the server.
+type RecurlyJSTokenProvider struct {
+	token string
+}
+
+// NewRecurlyJSTokenProvider returns a TokenProvider that hands back the
+// token recurly.js already minted client-side.
+func NewRecurlyJSTokenProvider(token string) *RecurlyJSTokenProvider {
+	return &RecurlyJSTokenProvider{token: token}
+}
+
+// Tokenize ignores card (recurly.js tokens never carry a PAN to this
+// library) and returns the token supplied to NewRecurlyJSTokenProvider.
+func (p *RecurlyJSTokenProvider) Tokenize(ctx context.Context, card RawCard) (string, error) {
+	if p.token == "" {
+		return "", fmt.Errorf("recurly: RecurlyJSTokenProvider requires a token minted by recurly.js")
+	}
+	return p.token, nil
+}
@@ -1,16 +1,28 @@
 package recurly
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 var _ BillingService = &billingImpl{}
 
+// requestDoer is the subset of *Client that billingImpl depends on. It
+// exists so tests can substitute a fake transport instead of making real
+// HTTP calls.
+type requestDoer interface {
+	newRequest(method, action string, params url.Values, body interface{}) (*http.Request, error)
+	do(req *http.Request, v interface{}) (*Response, error)
+}
+
+var _ requestDoer = &Client{}
+
 // billingImpl handles all interaction with the billing info portion
 // of the recurly API.
 type billingImpl struct {
-	client *Client
+	client requestDoer
 }
 
 // NewBillingImpl returns a new instance of billingImpl.
@@ -21,19 +33,28 @@ func NewBillingImpl(client *Client) *billingImpl {
 // Get returns only the account's current billing information.
 // https://docs.recurly.com/api/billing-info#lookup-billing-info
 func (s *billingImpl) Get(accountCode string) (*Response, *Billing, error) {
+	return s.GetContext(context.Background(), accountCode)
+}
+
+// GetContext is like Get but propagates ctx's cancellation to the
+// underlying HTTP request.
+func (s *billingImpl) GetContext(ctx context.Context, accountCode string) (*Response, *Billing, error) {
 	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
 	req, err := s.client.newRequest("GET", action, nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
+	req = req.WithContext(ctx)
 
-	var dst Billing
+	var dst billingResult
 	resp, err := s.client.do(req, &dst)
-	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+	if err != nil {
 		return resp, nil, err
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, dst.apiError(resp.StatusCode)
 	}
 
-	return resp, &dst, err
+	return resp, &dst.Billing, nil
 }
 
 // Create creates the account's billing information with credit card or
@@ -42,16 +63,28 @@ func (s *billingImpl) Get(accountCode string) (*Response, *Billing, error) {
 // https://dev.recurly.com/docs/create-an-accounts-billing-info-credit-card
 // https://dev.recurly.com/docs/create-an-accounts-billing-info-bank-account
 func (s *billingImpl) Create(accountCode string, b Billing) (*Response, *Billing, error) {
+	return s.CreateContext(context.Background(), accountCode, b)
+}
+
+// CreateContext is like Create but propagates ctx's cancellation to the
+// underlying HTTP request.
+func (s *billingImpl) CreateContext(ctx context.Context, accountCode string, b Billing) (*Response, *Billing, error) {
 	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
 	req, err := s.client.newRequest("POST", action, nil, b)
 	if err != nil {
 		return nil, nil, err
 	}
+	req = req.WithContext(ctx)
 
-	var dst Billing
+	var dst billingResult
 	resp, err := s.client.do(req, &dst)
+	if err != nil {
+		return resp, nil, err
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, dst.apiError(resp.StatusCode)
+	}
 
-	return resp, &dst, err
+	return resp, &dst.Billing, nil
 }
 
 // CreateWithToken creates an account's billing information using a token
@@ -64,10 +97,15 @@ func (s *billingImpl) CreateWithToken(accountCode string, token string) (*Respon
 		return nil, nil, err
 	}
 
-	var dst Billing
+	var dst billingResult
 	resp, err := s.client.do(req, &dst)
+	if err != nil {
+		return resp, nil, err
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, dst.apiError(resp.StatusCode)
+	}
 
-	return resp, &dst, err
+	return resp, &dst.Billing, nil
 }
 
 // Update updates the account's billing information with credit card or
@@ -76,28 +114,40 @@ func (s *billingImpl) CreateWithToken(accountCode string, token string) (*Respon
 // https://dev.recurly.com/docs/update-an-accounts-billing-info-credit-card
 // https://dev.recurly.com/docs/update-an-accounts-billing-info-bank-account
 func (s *billingImpl) Update(accountCode string, b Billing) (*Response, *Billing, error) {
+	return s.UpdateContext(context.Background(), accountCode, b)
+}
+
+// UpdateContext is like Update but propagates ctx's cancellation to the
+// underlying HTTP request.
+func (s *billingImpl) UpdateContext(ctx context.Context, accountCode string, b Billing) (*Response, *Billing, error) {
 	// Create clean billing object with write-only fields to avoid errors
 	// like sending additional/unknown/read-only fields.
 	clean := Billing{
-		FirstName:         b.FirstName,
-		LastName:          b.LastName,
-		Address:           b.Address,
-		Address2:          b.Address2,
-		City:              b.City,
-		State:             b.State,
-		Zip:               b.Zip,
-		Country:           b.Country,
-		Phone:             b.Phone,
-		VATNumber:         b.VATNumber,
-		IPAddress:         b.IPAddress,
-		Number:            b.Number,
-		Month:             b.Month,
-		Year:              b.Year,
-		VerificationValue: b.VerificationValue,
-		NameOnAccount:     b.NameOnAccount,
-		RoutingNumber:     b.RoutingNumber,
-		AccountNumber:     b.AccountNumber,
-		AccountType:       b.AccountType,
+		FirstName:                 b.FirstName,
+		LastName:                  b.LastName,
+		Address:                   b.Address,
+		Address2:                  b.Address2,
+		City:                      b.City,
+		State:                     b.State,
+		Zip:                       b.Zip,
+		Country:                   b.Country,
+		Phone:                     b.Phone,
+		VATNumber:                 b.VATNumber,
+		IPAddress:                 b.IPAddress,
+		Number:                    b.Number,
+		Month:                     b.Month,
+		Year:                      b.Year,
+		VerificationValue:         b.VerificationValue,
+		NameOnAccount:             b.NameOnAccount,
+		RoutingNumber:             b.RoutingNumber,
+		AccountNumber:             b.AccountNumber,
+		AccountType:               b.AccountType,
+		ThreeDSecureActionTokenID: b.ThreeDSecureActionTokenID,
+		PaymentMethod:             b.PaymentMethod,
+		SEPAMandate:               b.SEPAMandate,
+		BACSMandate:               b.BACSMandate,
+		PayPalBillingAgreementID:  b.PayPalBillingAgreementID,
+		AmazonBillingAgreementID:  b.AmazonBillingAgreementID,
 	}
 
 	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
@@ -105,11 +155,17 @@ func (s *billingImpl) Update(accountCode string, b Billing) (*Response, *Billing
 	if err != nil {
 		return nil, nil, err
 	}
+	req = req.WithContext(ctx)
 
-	var dst Billing
+	var dst billingResult
 	resp, err := s.client.do(req, &dst)
+	if err != nil {
+		return resp, nil, err
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, dst.apiError(resp.StatusCode)
+	}
 
-	return resp, &dst, err
+	return resp, &dst.Billing, nil
 }
 
 // UpdateWithToken updates an account's billing information using a token
@@ -122,10 +178,15 @@ func (s *billingImpl) UpdateWithToken(accountCode string, token string) (*Respon
 		return nil, nil, err
 	}
 
-	var dst Billing
+	var dst billingResult
 	resp, err := s.client.do(req, &dst)
+	if err != nil {
+		return resp, nil, err
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, dst.apiError(resp.StatusCode)
+	}
 
-	return resp, &dst, err
+	return resp, &dst.Billing, nil
 }
 
 // Clear removes any stored billing information for an account. If the account
@@ -133,11 +194,25 @@ func (s *billingImpl) UpdateWithToken(accountCode string, token string) (*Respon
 // billing info before the renewal occurs.
 // https://docs.recurly.com/api/billing-info#clear-billing-info
 func (s *billingImpl) Clear(accountCode string) (*Response, error) {
+	return s.ClearContext(context.Background(), accountCode)
+}
+
+// ClearContext is like Clear but propagates ctx's cancellation to the
+// underlying HTTP request.
+func (s *billingImpl) ClearContext(ctx context.Context, accountCode string) (*Response, error) {
 	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
 	req, err := s.client.newRequest("DELETE", action, nil, nil)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
-	return s.client.do(req, nil)
-}
\ No newline at end of file
+	resp, err := s.client.do(req, nil)
+	if err != nil {
+		return resp, err
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		return resp, &APIError{StatusCode: resp.StatusCode}
+	}
+
+	return resp, nil
+}
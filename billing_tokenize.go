@@ -0,0 +1,68 @@
+package recurly
+
+import (
+	"context"
+)
+
+// RawCard holds the raw PAN and related card details collected from a
+// customer before they've been exchanged for a gateway token. It should
+// never be sent directly to Create or Update; pass it to a TokenProvider
+// via CreateWithProvider/UpdateWithProvider instead so only the resulting
+// token, never the PAN, is sent to Recurly.
+type RawCard struct {
+	FirstName         string
+	LastName          string
+	Number            string
+	Month             int
+	Year              int
+	VerificationValue string
+}
+
+// TokenProvider exchanges raw card details for a single-use or vault
+// token that can be submitted to Recurly via CreateWithToken/
+// UpdateWithToken, keeping the PAN out of every request sent to Recurly.
+type TokenProvider interface {
+	Tokenize(ctx context.Context, card RawCard) (string, error)
+}
+
+// CreateWithProvider tokenizes card via provider and creates the
+// account's billing information from the resulting token, so the PAN
+// itself is never sent to Recurly.
+func (s *billingImpl) CreateWithProvider(accountCode string, card RawCard, provider TokenProvider) (*Response, *Billing, error) {
+	token, err := provider.Tokenize(context.Background(), card)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.CreateWithToken(accountCode, token)
+}
+
+// UpdateWithProvider tokenizes card via provider and updates the
+// account's billing information from the resulting token, so the PAN
+// itself is never sent to Recurly.
+func (s *billingImpl) UpdateWithProvider(accountCode string, card RawCard, provider TokenProvider) (*Response, *Billing, error) {
+	token, err := provider.Tokenize(context.Background(), card)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.UpdateWithToken(accountCode, token)
+}
+
+// CreateWith3DS creates the account's billing information together with
+// the 3-D Secure 2 action token collected during strong customer
+// authentication (SCA), as required for EU card flows under PSD2.
+// https://dev.recurly.com/docs/3-d-secure-2
+func (s *billingImpl) CreateWith3DS(accountCode string, b Billing, actionTokenID string) (*Response, *Billing, error) {
+	b.ThreeDSecureActionTokenID = actionTokenID
+	return s.Create(accountCode, b)
+}
+
+// UpdateWith3DS updates the account's billing information together with
+// the 3-D Secure 2 action token collected during strong customer
+// authentication (SCA), as required for EU card flows under PSD2.
+// https://dev.recurly.com/docs/3-d-secure-2
+func (s *billingImpl) UpdateWith3DS(accountCode string, b Billing, actionTokenID string) (*Response, *Billing, error) {
+	b.ThreeDSecureActionTokenID = actionTokenID
+	return s.Update(accountCode, b)
+}
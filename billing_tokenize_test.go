@@ -0,0 +1,78 @@
+package recurly
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCreateWithProviderPropagatesTokenizeError(t *testing.T) {
+	wantErr := errors.New("tokenize: declined by gateway")
+	provider := FuncTokenProvider(func(ctx context.Context, card RawCard) (string, error) {
+		return "", wantErr
+	})
+
+	s := &billingImpl{}
+	_, billing, err := s.CreateWithProvider("abc123", RawCard{}, provider)
+	if billing != nil {
+		t.Errorf("expected nil billing, got %+v", billing)
+	}
+	if !errors.Is(err, wantErr) {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestUpdateWithProviderPropagatesTokenizeError(t *testing.T) {
+	wantErr := errors.New("tokenize: declined by gateway")
+	provider := FuncTokenProvider(func(ctx context.Context, card RawCard) (string, error) {
+		return "", wantErr
+	})
+
+	s := &billingImpl{}
+	_, billing, err := s.UpdateWithProvider("abc123", RawCard{}, provider)
+	if billing != nil {
+		t.Errorf("expected nil billing, got %+v", billing)
+	}
+	if !errors.Is(err, wantErr) {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestFuncTokenProviderCallsUnderlyingFunc(t *testing.T) {
+	var gotCard RawCard
+	provider := FuncTokenProvider(func(ctx context.Context, card RawCard) (string, error) {
+		gotCard = card
+		return "tok_abc", nil
+	})
+
+	token, err := provider.Tokenize(context.Background(), RawCard{Number: "4111111111111111"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "tok_abc" {
+		t.Errorf("token = %q, want %q", token, "tok_abc")
+	}
+	if gotCard.Number != "4111111111111111" {
+		t.Errorf("card was not passed through to the underlying func: %+v", gotCard)
+	}
+}
+
+func TestRecurlyJSTokenProviderErrorsOnEmptyToken(t *testing.T) {
+	provider := NewRecurlyJSTokenProvider("")
+
+	if _, err := provider.Tokenize(context.Background(), RawCard{}); err == nil {
+		t.Fatal("expected an error for an empty token")
+	}
+}
+
+func TestRecurlyJSTokenProviderReturnsConfiguredToken(t *testing.T) {
+	provider := NewRecurlyJSTokenProvider("tok_from_js")
+
+	token, err := provider.Tokenize(context.Background(), RawCard{Number: "4111111111111111"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "tok_from_js" {
+		t.Errorf("token = %q, want %q", token, "tok_from_js")
+	}
+}
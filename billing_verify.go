@@ -0,0 +1,87 @@
+package recurly
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// VerifyOptions specifies the gateway and amount to use when verifying
+// stored billing information with a zero-dollar (or small-amount) auth.
+// Amount and Currency may be left zero to let the gateway pick its own
+// default verification amount.
+type VerifyOptions struct {
+	GatewayCode string `xml:"gateway_code,omitempty"`
+	Amount      int    `xml:"amount,omitempty"`
+	Currency    string `xml:"currency,omitempty"`
+}
+
+// Verify triggers a zero-dollar (or small-amount) authorization against an
+// account's stored billing info to confirm it's still valid, without
+// waiting for a renewal to fail.
+// https://dev.recurly.com/docs/verify-billing-info
+func (s *billingImpl) Verify(accountCode string, opts *VerifyOptions) (*Response, *Transaction, error) {
+	action := fmt.Sprintf("accounts/%s/billing_info/verify", accountCode)
+	return s.verify(action, opts)
+}
+
+// VerifyCVV performs a CVV-only verification of an account's stored
+// billing info, without running a full authorization.
+// https://dev.recurly.com/docs/verify-billing-info-cvv
+func (s *billingImpl) VerifyCVV(accountCode string, opts *VerifyOptions) (*Response, *Transaction, error) {
+	action := fmt.Sprintf("accounts/%s/billing_info/verify_cvv", accountCode)
+	return s.verify(action, opts)
+}
+
+func (s *billingImpl) verify(action string, opts *VerifyOptions) (*Response, *Transaction, error) {
+	var body interface{}
+	if opts != nil {
+		body = opts
+	}
+
+	req, err := s.client.newRequest("POST", action, nil, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst transactionResult
+	resp, err := s.client.do(req, &dst)
+	if err != nil {
+		return resp, nil, err
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, dst.apiError(resp.StatusCode)
+	}
+
+	return resp, &dst.Transaction, nil
+}
+
+// CreateWithVerification creates the account's billing information and
+// immediately verifies it with a zero-dollar (or small-amount)
+// authorization, returning an error if the gateway declines it.
+func (s *billingImpl) CreateWithVerification(accountCode string, b Billing, opts *VerifyOptions) (*Response, *Billing, error) {
+	resp, billing, err := s.Create(accountCode, b)
+	if err != nil {
+		return resp, billing, err
+	}
+
+	if _, _, err := s.Verify(accountCode, opts); err != nil {
+		return resp, billing, err
+	}
+
+	return resp, billing, nil
+}
+
+// UpdateWithVerification updates the account's billing information and
+// immediately verifies it with a zero-dollar (or small-amount)
+// authorization, returning an error if the gateway declines it.
+func (s *billingImpl) UpdateWithVerification(accountCode string, b Billing, opts *VerifyOptions) (*Response, *Billing, error) {
+	resp, billing, err := s.Update(accountCode, b)
+	if err != nil {
+		return resp, billing, err
+	}
+
+	if _, _, err := s.Verify(accountCode, opts); err != nil {
+		return resp, billing, err
+	}
+
+	return resp, billing, nil
+}
@@ -0,0 +1,141 @@
+package recurly
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const declinedTransactionXML = `<transaction>
+	<transaction_error>
+		<error_code>invalid_account_balance</error_code>
+		<error_category>soft</error_category>
+		<merchant_message>The card was declined.</merchant_message>
+		<customer_message>Your card was declined.</customer_message>
+	</transaction_error>
+</transaction>`
+
+func TestVerifyDeclined(t *testing.T) {
+	fake := &fakeRequestDoer{
+		doFunc: func(req *http.Request, v interface{}) (*Response, error) {
+			if err := xml.Unmarshal([]byte(declinedTransactionXML), v); err != nil {
+				t.Fatalf("unmarshal fixture: %v", err)
+			}
+			return &Response{StatusCode: http.StatusUnprocessableEntity}, nil
+		},
+	}
+
+	s := &billingImpl{client: fake}
+	_, txn, err := s.Verify("abc123", nil)
+	if txn != nil {
+		t.Fatalf("expected nil transaction on decline, got %+v", txn)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, http.StatusUnprocessableEntity)
+	}
+	if apiErr.TransactionError == nil || apiErr.TransactionError.ErrorCode != "invalid_account_balance" {
+		t.Errorf("unexpected TransactionError: %+v", apiErr.TransactionError)
+	}
+	if !IsDeclined(err) {
+		t.Error("expected IsDeclined(err) to be true")
+	}
+}
+
+func TestVerifyCVVDeclined(t *testing.T) {
+	fake := &fakeRequestDoer{
+		doFunc: func(req *http.Request, v interface{}) (*Response, error) {
+			if !strings.HasSuffix(req.URL.Path, "verify_cvv") {
+				t.Fatalf("expected verify_cvv action, got %s", req.URL.Path)
+			}
+			if err := xml.Unmarshal([]byte(declinedTransactionXML), v); err != nil {
+				t.Fatalf("unmarshal fixture: %v", err)
+			}
+			return &Response{StatusCode: http.StatusUnprocessableEntity}, nil
+		},
+	}
+
+	s := &billingImpl{client: fake}
+	if _, _, err := s.VerifyCVV("abc123", nil); !IsDeclined(err) {
+		t.Fatalf("expected IsDeclined(err) to be true, got %v", err)
+	}
+}
+
+func TestVerifySuccess(t *testing.T) {
+	fake := &fakeRequestDoer{
+		doFunc: func(req *http.Request, v interface{}) (*Response, error) {
+			return &Response{StatusCode: http.StatusOK}, nil
+		},
+	}
+
+	s := &billingImpl{client: fake}
+	_, txn, err := s.Verify("abc123", &VerifyOptions{Amount: 100, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if txn == nil {
+		t.Fatal("expected a non-nil transaction")
+	}
+}
+
+func TestCreateWithVerificationPropagatesDecline(t *testing.T) {
+	const billingXML = `<billing_info><first_name>Verena</first_name></billing_info>`
+
+	fake := &fakeRequestDoer{
+		doFunc: func(req *http.Request, v interface{}) (*Response, error) {
+			if strings.Contains(req.URL.Path, "verify") {
+				if err := xml.Unmarshal([]byte(declinedTransactionXML), v); err != nil {
+					t.Fatalf("unmarshal fixture: %v", err)
+				}
+				return &Response{StatusCode: http.StatusUnprocessableEntity}, nil
+			}
+			if err := xml.Unmarshal([]byte(billingXML), v); err != nil {
+				t.Fatalf("unmarshal fixture: %v", err)
+			}
+			return &Response{StatusCode: http.StatusOK}, nil
+		},
+	}
+
+	s := &billingImpl{client: fake}
+	_, billing, err := s.CreateWithVerification("abc123", Billing{FirstName: "Verena"}, nil)
+	if billing == nil || billing.FirstName != "Verena" {
+		t.Fatalf("expected the created billing info back even though verification failed, got %+v", billing)
+	}
+	if !IsDeclined(err) {
+		t.Fatalf("expected IsDeclined(err) to be true, got %v", err)
+	}
+}
+
+func TestUpdateWithVerificationPropagatesDecline(t *testing.T) {
+	const billingXML = `<billing_info><first_name>Verena</first_name></billing_info>`
+
+	fake := &fakeRequestDoer{
+		doFunc: func(req *http.Request, v interface{}) (*Response, error) {
+			if strings.Contains(req.URL.Path, "verify") {
+				if err := xml.Unmarshal([]byte(declinedTransactionXML), v); err != nil {
+					t.Fatalf("unmarshal fixture: %v", err)
+				}
+				return &Response{StatusCode: http.StatusUnprocessableEntity}, nil
+			}
+			if err := xml.Unmarshal([]byte(billingXML), v); err != nil {
+				t.Fatalf("unmarshal fixture: %v", err)
+			}
+			return &Response{StatusCode: http.StatusOK}, nil
+		},
+	}
+
+	s := &billingImpl{client: fake}
+	_, billing, err := s.UpdateWithVerification("abc123", Billing{FirstName: "Verena"}, nil)
+	if billing == nil || billing.FirstName != "Verena" {
+		t.Fatalf("expected the updated billing info back even though verification failed, got %+v", billing)
+	}
+	if !IsDeclined(err) {
+		t.Fatalf("expected IsDeclined(err) to be true, got %v", err)
+	}
+}
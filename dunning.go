@@ -0,0 +1,176 @@
+package recurly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DunningEvent describes the outcome of a single collection attempt made
+// by DunningService.Run.
+type DunningEvent struct {
+	AccountCode   string
+	InvoiceNumber int
+	Attempt       int
+	NextRetry     time.Time
+	Outcome       string
+	Err           error
+}
+
+// DunningPolicy configures how DunningService retries past-due invoices.
+type DunningPolicy struct {
+	// RetrySchedule is the delay before each successive collection
+	// attempt. len(RetrySchedule) is the maximum number of attempts
+	// unless MaxAttempts further restricts it.
+	RetrySchedule []time.Duration
+
+	// MaxAttempts caps the number of attempts. Zero means use the full
+	// length of RetrySchedule.
+	MaxAttempts int
+
+	// OnFinalFailure is invoked once an invoice exhausts its retry
+	// schedule without a successful collection, e.g. to downgrade or
+	// cancel the account's subscriptions.
+	OnFinalFailure func(accountCode string) error
+
+	// NotifyHook, if set, is called after every collection attempt.
+	NotifyHook func(DunningEvent)
+}
+
+// invoiceLister is the subset of InvoicesService that DunningService
+// depends on. It exists so tests can substitute a fake instead of
+// needing a real InvoicesService.
+type invoiceLister interface {
+	List(accountCode string) (*Response, []Invoice, error)
+}
+
+var _ invoiceLister = InvoicesService(nil)
+
+// collector is the subset of billingImpl that DunningService depends
+// on. It exists so tests can substitute a fake instead of needing a
+// real *Client.
+type collector interface {
+	Collect(accountCode string, invoiceNumber int) (*Response, *Transaction, error)
+}
+
+var _ collector = &billingImpl{}
+
+// DunningService drives configurable overdue-payment handling by
+// coupling billingImpl with InvoicesService: it walks an account's
+// past-due invoices and re-attempts collection on a retry schedule,
+// invoking OnFinalFailure once the schedule is exhausted.
+type DunningService struct {
+	billing  collector
+	invoices invoiceLister
+	policy   DunningPolicy
+}
+
+// NewDunningService returns a new DunningService driven by the given
+// billing and invoices services, following policy.
+func NewDunningService(billing *billingImpl, invoices InvoicesService, policy DunningPolicy) *DunningService {
+	return &DunningService{billing: billing, invoices: invoices, policy: policy}
+}
+
+// Run walks the account's past-due invoices and re-attempts collection on
+// each according to the configured DunningPolicy. A single invoice
+// exhausting its retry schedule does not stop Run from attempting the
+// rest; Run returns an error summarizing every invoice that still
+// couldn't be collected once the walk completes. Run stops early only if
+// ctx is canceled, since that also aborts every remaining attempt.
+func (d *DunningService) Run(ctx context.Context, accountCode string) error {
+	_, invoices, err := d.invoices.List(accountCode)
+	if err != nil {
+		return err
+	}
+
+	var pastDue int
+	var errs []error
+	for _, inv := range invoices {
+		if inv.State != "past_due" {
+			continue
+		}
+		pastDue++
+
+		if err := d.collect(ctx, accountCode, inv.InvoiceNumber); err != nil {
+			if ctx.Err() != nil {
+				// ctx governs every remaining attempt too; no point
+				// continuing the walk.
+				return err
+			}
+			errs = append(errs, fmt.Errorf("invoice %d: %w", inv.InvoiceNumber, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("recurly: dunning failed for %d of %d past-due invoice(s), first error: %w", len(errs), pastDue, errs[0])
+	}
+
+	return nil
+}
+
+func (d *DunningService) collect(ctx context.Context, accountCode string, invoiceNumber int) error {
+	attempts := len(d.policy.RetrySchedule)
+	if d.policy.MaxAttempts > 0 && d.policy.MaxAttempts < attempts {
+		attempts = d.policy.MaxAttempts
+	}
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d.policy.RetrySchedule[attempt]):
+		}
+
+		resp, _, err := d.billing.Collect(accountCode, invoiceNumber)
+		event := DunningEvent{
+			AccountCode:   accountCode,
+			InvoiceNumber: invoiceNumber,
+			Attempt:       attempt + 1,
+			Outcome:       "failed",
+			Err:           err,
+		}
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			event.Outcome = "succeeded"
+		}
+		if event.Outcome == "failed" && attempt+1 < attempts {
+			event.NextRetry = time.Now().Add(d.policy.RetrySchedule[attempt+1])
+		}
+
+		if d.policy.NotifyHook != nil {
+			d.policy.NotifyHook(event)
+		}
+
+		if event.Outcome == "succeeded" {
+			return nil
+		}
+	}
+
+	if d.policy.OnFinalFailure != nil {
+		if err := d.policy.OnFinalFailure(accountCode); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("recurly: dunning exhausted retry schedule for invoice %d on account %s", invoiceNumber, accountCode)
+}
+
+// Collect re-attempts collection of a past-due invoice.
+// https://dev.recurly.com/docs/mark-invoice-successful-collect-invoice
+func (s *billingImpl) Collect(accountCode string, invoiceNumber int) (*Response, *Transaction, error) {
+	action := fmt.Sprintf("invoices/%d/collect", invoiceNumber)
+	req, err := s.client.newRequest("POST", action, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst transactionResult
+	resp, err := s.client.do(req, &dst)
+	if err != nil {
+		return resp, nil, err
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, dst.apiError(resp.StatusCode)
+	}
+
+	return resp, &dst.Transaction, nil
+}
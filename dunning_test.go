@@ -0,0 +1,156 @@
+package recurly
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type fakeInvoiceLister struct {
+	invoices []Invoice
+	err      error
+}
+
+func (f *fakeInvoiceLister) List(accountCode string) (*Response, []Invoice, error) {
+	return &Response{StatusCode: http.StatusOK}, f.invoices, f.err
+}
+
+type fakeCollector struct {
+	// collectFunc decides the outcome for a given invoice/attempt. Calls
+	// are recorded in attempts, keyed by invoice number.
+	collectFunc func(invoiceNumber int, attempt int) (*Response, error)
+	attempts    map[int]int
+}
+
+func (f *fakeCollector) Collect(accountCode string, invoiceNumber int) (*Response, *Transaction, error) {
+	if f.attempts == nil {
+		f.attempts = make(map[int]int)
+	}
+	f.attempts[invoiceNumber]++
+	resp, err := f.collectFunc(invoiceNumber, f.attempts[invoiceNumber])
+	return resp, &Transaction{}, err
+}
+
+func tinySchedule(n int) []time.Duration {
+	sched := make([]time.Duration, n)
+	for i := range sched {
+		sched[i] = time.Millisecond
+	}
+	return sched
+}
+
+func TestDunningServiceRunPartialFailure(t *testing.T) {
+	invoices := &fakeInvoiceLister{invoices: []Invoice{
+		{InvoiceNumber: 1, State: "past_due"},
+		{InvoiceNumber: 2, State: "past_due"},
+		{InvoiceNumber: 3, State: "collected"},
+	}}
+
+	billing := &fakeCollector{collectFunc: func(invoiceNumber int, attempt int) (*Response, error) {
+		if invoiceNumber == 2 {
+			return &Response{StatusCode: http.StatusOK}, nil
+		}
+		return &Response{StatusCode: http.StatusUnprocessableEntity}, &APIError{StatusCode: http.StatusUnprocessableEntity, Symbol: "declined"}
+	}}
+
+	d := &DunningService{
+		billing:  billing,
+		invoices: invoices,
+		policy:   DunningPolicy{RetrySchedule: tinySchedule(2)},
+	}
+
+	err := d.Run(context.Background(), "acct1")
+	if err == nil {
+		t.Fatal("expected an error summarizing the invoice that never collected")
+	}
+	if billing.attempts[1] != 2 {
+		t.Errorf("invoice 1 should have exhausted both attempts, got %d", billing.attempts[1])
+	}
+	if billing.attempts[2] != 1 {
+		t.Errorf("invoice 2 should have succeeded on the first attempt, got %d", billing.attempts[2])
+	}
+	if _, ok := billing.attempts[3]; ok {
+		t.Error("invoice 3 is not past_due and should never have been collected")
+	}
+}
+
+func TestDunningServiceCollectRespectsMaxAttempts(t *testing.T) {
+	invoices := &fakeInvoiceLister{invoices: []Invoice{{InvoiceNumber: 1, State: "past_due"}}}
+	billing := &fakeCollector{collectFunc: func(invoiceNumber int, attempt int) (*Response, error) {
+		return &Response{StatusCode: http.StatusUnprocessableEntity}, &APIError{StatusCode: http.StatusUnprocessableEntity, Symbol: "declined"}
+	}}
+
+	d := &DunningService{
+		billing:  billing,
+		invoices: invoices,
+		policy:   DunningPolicy{RetrySchedule: tinySchedule(5), MaxAttempts: 2},
+	}
+
+	if err := d.Run(context.Background(), "acct1"); err == nil {
+		t.Fatal("expected an error once the capped attempts are exhausted")
+	}
+	if billing.attempts[1] != 2 {
+		t.Errorf("MaxAttempts should have capped collection at 2 attempts, got %d", billing.attempts[1])
+	}
+}
+
+func TestDunningServiceNotifyHookFiresPerAttempt(t *testing.T) {
+	invoices := &fakeInvoiceLister{invoices: []Invoice{{InvoiceNumber: 1, State: "past_due"}}}
+	billing := &fakeCollector{collectFunc: func(invoiceNumber int, attempt int) (*Response, error) {
+		if attempt == 2 {
+			return &Response{StatusCode: http.StatusOK}, nil
+		}
+		return &Response{StatusCode: http.StatusUnprocessableEntity}, &APIError{StatusCode: http.StatusUnprocessableEntity, Symbol: "declined"}
+	}}
+
+	var events []DunningEvent
+	d := &DunningService{
+		billing:  billing,
+		invoices: invoices,
+		policy: DunningPolicy{
+			RetrySchedule: tinySchedule(3),
+			NotifyHook:    func(e DunningEvent) { events = append(events, e) },
+		},
+	}
+
+	if err := d.Run(context.Background(), "acct1"); err != nil {
+		t.Fatalf("expected the second attempt to succeed, got %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected NotifyHook to fire once per attempt, got %d events", len(events))
+	}
+	if events[0].Outcome != "failed" || events[0].Attempt != 1 {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+	if events[1].Outcome != "succeeded" || events[1].Attempt != 2 {
+		t.Errorf("unexpected second event: %+v", events[1])
+	}
+}
+
+func TestDunningServiceRunStopsOnContextCancellation(t *testing.T) {
+	invoices := &fakeInvoiceLister{invoices: []Invoice{
+		{InvoiceNumber: 1, State: "past_due"},
+		{InvoiceNumber: 2, State: "past_due"},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	billing := &fakeCollector{collectFunc: func(invoiceNumber int, attempt int) (*Response, error) {
+		cancel()
+		return &Response{StatusCode: http.StatusUnprocessableEntity}, &APIError{StatusCode: http.StatusUnprocessableEntity, Symbol: "declined"}
+	}}
+
+	d := &DunningService{
+		billing:  billing,
+		invoices: invoices,
+		policy:   DunningPolicy{RetrySchedule: tinySchedule(3)},
+	}
+
+	err := d.Run(ctx, "acct1")
+	if err == nil {
+		t.Fatal("expected an error from the canceled context")
+	}
+	if billing.attempts[2] != 0 {
+		t.Error("Run should have aborted before reaching invoice 2 once ctx was canceled")
+	}
+}
@@ -0,0 +1,26 @@
+package recurly
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+)
+
+// fakeRequestDoer is a requestDoer test double that lets tests control
+// the *http.Request produced and the decoded response/error returned,
+// without making a real HTTP call.
+type fakeRequestDoer struct {
+	newRequestFunc func(method, action string, params url.Values, body interface{}) (*http.Request, error)
+	doFunc         func(req *http.Request, v interface{}) (*Response, error)
+}
+
+func (f *fakeRequestDoer) newRequest(method, action string, params url.Values, body interface{}) (*http.Request, error) {
+	if f.newRequestFunc != nil {
+		return f.newRequestFunc(method, action, params, body)
+	}
+	return httptest.NewRequest(method, "/"+action, nil), nil
+}
+
+func (f *fakeRequestDoer) do(req *http.Request, v interface{}) (*Response, error) {
+	return f.doFunc(req, v)
+}
@@ -0,0 +1,234 @@
+// Package webhooks parses Recurly's XML push notifications and dispatches
+// them to typed handler interfaces.
+// https://dev.recurly.com/page/webhooks
+package webhooks
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/haleychen/recurly"
+)
+
+// SeenStore de-duplicates webhook deliveries so retried notifications
+// aren't processed twice. Implementations are expected to be safe for
+// concurrent use.
+type SeenStore interface {
+	// Seen reports whether id has already been recorded, recording it
+	// if not.
+	Seen(id string) (bool, error)
+}
+
+// BillingInfoUpdatedHandler handles updated_billing_info_notification and
+// billing_info_updated_notification pushes.
+type BillingInfoUpdatedHandler interface {
+	OnBillingInfoUpdated(ctx context.Context, billing *recurly.Billing, account *recurly.Account) error
+}
+
+// NewAccountHandler handles new_account_notification pushes.
+type NewAccountHandler interface {
+	OnNewAccount(ctx context.Context, account *recurly.Account) error
+}
+
+// ExpiredSubscriptionHandler handles expired_subscription_notification
+// pushes.
+type ExpiredSubscriptionHandler interface {
+	OnExpiredSubscription(ctx context.Context, account *recurly.Account, sub *recurly.Subscription) error
+}
+
+// SuccessfulPaymentHandler handles successful_payment_notification
+// pushes.
+type SuccessfulPaymentHandler interface {
+	OnSuccessfulPayment(ctx context.Context, account *recurly.Account, txn *recurly.Transaction) error
+}
+
+// FailedPaymentHandler handles failed_payment_notification pushes.
+type FailedPaymentHandler interface {
+	OnFailedPayment(ctx context.Context, account *recurly.Account, txn *recurly.Transaction) error
+}
+
+// Handler is an http.Handler that verifies and dispatches Recurly webhook
+// notifications. Any handler field left nil is simply ignored for its
+// corresponding notification type.
+type Handler struct {
+	// Username and Password are the HTTP Basic-auth credentials
+	// configured in Recurly's webhook settings. If Username is empty,
+	// authentication is skipped (not recommended outside of tests).
+	Username string
+	Password string
+
+	// Seen, if set, is used to discard notifications whose body has
+	// already been delivered. A body is recorded before its handler runs,
+	// so a redelivery after a handler error is discarded as well.
+	Seen SeenStore
+
+	BillingInfoUpdated  BillingInfoUpdatedHandler
+	NewAccount          NewAccountHandler
+	ExpiredSubscription ExpiredSubscriptionHandler
+	SuccessfulPayment   SuccessfulPaymentHandler
+	FailedPayment       FailedPaymentHandler
+}
+
+var _ http.Handler = &Handler{}
+
+// ServeHTTP implements http.Handler, allowing a Handler to be mounted
+// directly, e.g. at /recurly/webhooks.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authenticate(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="recurly webhooks"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "could not read request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.dispatch(r.Context(), body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) authenticate(r *http.Request) bool {
+	if h.Username == "" {
+		return true
+	}
+
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	userMatch := subtle.ConstantTimeCompare([]byte(user), []byte(h.Username)) == 1
+	passMatch := subtle.ConstantTimeCompare([]byte(pass), []byte(h.Password)) == 1
+	return userMatch && passMatch
+}
+
+// dispatch decodes the notification envelope to determine its type, checks
+// it against Seen, and invokes the matching typed handler.
+func (h *Handler) dispatch(ctx context.Context, body []byte) error {
+	var envelope struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("webhooks: could not parse notification: %w", err)
+	}
+
+	if h.Seen != nil {
+		sum := sha1.Sum(body)
+		id := hex.EncodeToString(sum[:])
+		seen, err := h.Seen.Seen(id)
+		if err != nil {
+			return fmt.Errorf("webhooks: could not check seen store: %w", err)
+		} else if seen {
+			return nil
+		}
+	}
+
+	switch envelope.XMLName.Local {
+	case "new_account_notification":
+		return h.dispatchNewAccount(ctx, body)
+	case "updated_billing_info_notification", "billing_info_updated_notification":
+		return h.dispatchBillingInfoUpdated(ctx, body)
+	case "expired_subscription_notification":
+		return h.dispatchExpiredSubscription(ctx, body)
+	case "successful_payment_notification":
+		return h.dispatchSuccessfulPayment(ctx, body)
+	case "failed_payment_notification":
+		return h.dispatchFailedPayment(ctx, body)
+	default:
+		// Unrecognized notification types are acknowledged but ignored
+		// so Recurly doesn't keep retrying them.
+		return nil
+	}
+}
+
+func (h *Handler) dispatchNewAccount(ctx context.Context, body []byte) error {
+	if h.NewAccount == nil {
+		return nil
+	}
+
+	var n struct {
+		Account recurly.Account `xml:"account"`
+	}
+	if err := xml.Unmarshal(body, &n); err != nil {
+		return err
+	}
+
+	return h.NewAccount.OnNewAccount(ctx, &n.Account)
+}
+
+func (h *Handler) dispatchBillingInfoUpdated(ctx context.Context, body []byte) error {
+	if h.BillingInfoUpdated == nil {
+		return nil
+	}
+
+	var n struct {
+		Account recurly.Account `xml:"account"`
+		Billing recurly.Billing `xml:"billing_info"`
+	}
+	if err := xml.Unmarshal(body, &n); err != nil {
+		return err
+	}
+
+	return h.BillingInfoUpdated.OnBillingInfoUpdated(ctx, &n.Billing, &n.Account)
+}
+
+func (h *Handler) dispatchExpiredSubscription(ctx context.Context, body []byte) error {
+	if h.ExpiredSubscription == nil {
+		return nil
+	}
+
+	var n struct {
+		Account      recurly.Account      `xml:"account"`
+		Subscription recurly.Subscription `xml:"subscription"`
+	}
+	if err := xml.Unmarshal(body, &n); err != nil {
+		return err
+	}
+
+	return h.ExpiredSubscription.OnExpiredSubscription(ctx, &n.Account, &n.Subscription)
+}
+
+func (h *Handler) dispatchSuccessfulPayment(ctx context.Context, body []byte) error {
+	if h.SuccessfulPayment == nil {
+		return nil
+	}
+
+	var n struct {
+		Account     recurly.Account     `xml:"account"`
+		Transaction recurly.Transaction `xml:"transaction"`
+	}
+	if err := xml.Unmarshal(body, &n); err != nil {
+		return err
+	}
+
+	return h.SuccessfulPayment.OnSuccessfulPayment(ctx, &n.Account, &n.Transaction)
+}
+
+func (h *Handler) dispatchFailedPayment(ctx context.Context, body []byte) error {
+	if h.FailedPayment == nil {
+		return nil
+	}
+
+	var n struct {
+		Account     recurly.Account     `xml:"account"`
+		Transaction recurly.Transaction `xml:"transaction"`
+	}
+	if err := xml.Unmarshal(body, &n); err != nil {
+		return err
+	}
+
+	return h.FailedPayment.OnFailedPayment(ctx, &n.Account, &n.Transaction)
+}
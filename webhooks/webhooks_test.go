@@ -0,0 +1,256 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/haleychen/recurly"
+)
+
+type fakeSeenStore struct {
+	seen map[string]bool
+}
+
+func (f *fakeSeenStore) Seen(id string) (bool, error) {
+	if f.seen == nil {
+		f.seen = make(map[string]bool)
+	}
+	wasSeen := f.seen[id]
+	f.seen[id] = true
+	return wasSeen, nil
+}
+
+type recordingHandlers struct {
+	newAccountCalls          int
+	billingInfoUpdatedCalls  int
+	expiredSubscriptionCalls int
+	successfulPaymentCalls   int
+	failedPaymentCalls       int
+
+	gotBilling *recurly.Billing
+}
+
+func (r *recordingHandlers) OnNewAccount(ctx context.Context, account *recurly.Account) error {
+	r.newAccountCalls++
+	return nil
+}
+
+func (r *recordingHandlers) OnBillingInfoUpdated(ctx context.Context, billing *recurly.Billing, account *recurly.Account) error {
+	r.billingInfoUpdatedCalls++
+	r.gotBilling = billing
+	return nil
+}
+
+func (r *recordingHandlers) OnExpiredSubscription(ctx context.Context, account *recurly.Account, sub *recurly.Subscription) error {
+	r.expiredSubscriptionCalls++
+	return nil
+}
+
+func (r *recordingHandlers) OnSuccessfulPayment(ctx context.Context, account *recurly.Account, txn *recurly.Transaction) error {
+	r.successfulPaymentCalls++
+	return nil
+}
+
+func (r *recordingHandlers) OnFailedPayment(ctx context.Context, account *recurly.Account, txn *recurly.Transaction) error {
+	r.failedPaymentCalls++
+	return nil
+}
+
+const newAccountXML = `<new_account_notification>
+	<account>
+		<account_code>1</account_code>
+	</account>
+</new_account_notification>`
+
+const billingInfoUpdatedXML = `<billing_info_updated_notification>
+	<account>
+		<account_code>1</account_code>
+	</account>
+	<billing_info>
+		<first_name>Verena</first_name>
+	</billing_info>
+</billing_info_updated_notification>`
+
+const expiredSubscriptionXML = `<expired_subscription_notification>
+	<account>
+		<account_code>1</account_code>
+	</account>
+	<subscription>
+		<plan_code>gold</plan_code>
+	</subscription>
+</expired_subscription_notification>`
+
+const successfulPaymentXML = `<successful_payment_notification>
+	<account>
+		<account_code>1</account_code>
+	</account>
+	<transaction>
+		<id>a5143c1d3a6f4a8287d0e2a2f1e5fa54</id>
+	</transaction>
+</successful_payment_notification>`
+
+const failedPaymentXML = `<failed_payment_notification>
+	<account>
+		<account_code>1</account_code>
+	</account>
+	<transaction>
+		<id>a5143c1d3a6f4a8287d0e2a2f1e5fa54</id>
+	</transaction>
+</failed_payment_notification>`
+
+func postWebhook(h *Handler, body string, user, pass string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/recurly/webhooks", strings.NewReader(body))
+	if user != "" || pass != "" {
+		req.SetBasicAuth(user, pass)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandlerAuthenticateRejectsWrongOrMissingCredentials(t *testing.T) {
+	rec := &recordingHandlers{}
+	h := &Handler{Username: "webhooks", Password: "secret", NewAccount: rec}
+
+	if w := postWebhook(h, newAccountXML, "webhooks", "wrong"); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong password: status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if w := postWebhook(h, newAccountXML, "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("missing credentials: status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if rec.newAccountCalls != 0 {
+		t.Errorf("handler should not have been invoked for unauthenticated requests, got %d calls", rec.newAccountCalls)
+	}
+
+	if w := postWebhook(h, newAccountXML, "webhooks", "secret"); w.Code != http.StatusOK {
+		t.Errorf("correct credentials: status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if rec.newAccountCalls != 1 {
+		t.Errorf("expected the handler to run once credentials are correct, got %d calls", rec.newAccountCalls)
+	}
+}
+
+func TestHandlerSkipsAuthWhenUsernameUnset(t *testing.T) {
+	rec := &recordingHandlers{}
+	h := &Handler{NewAccount: rec}
+
+	if w := postWebhook(h, newAccountXML, "", ""); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if rec.newAccountCalls != 1 {
+		t.Errorf("expected the handler to run, got %d calls", rec.newAccountCalls)
+	}
+}
+
+func TestHandlerDedupesRepeatedDeliveries(t *testing.T) {
+	rec := &recordingHandlers{}
+	h := &Handler{NewAccount: rec, Seen: &fakeSeenStore{}}
+
+	for i := 0; i < 2; i++ {
+		if w := postWebhook(h, newAccountXML, "", ""); w.Code != http.StatusOK {
+			t.Fatalf("delivery %d: status = %d, want %d", i, w.Code, http.StatusOK)
+		}
+	}
+
+	if rec.newAccountCalls != 1 {
+		t.Errorf("expected a repeated delivery to be deduped, got %d calls", rec.newAccountCalls)
+	}
+}
+
+func TestHandlerDispatchesNewAccount(t *testing.T) {
+	rec := &recordingHandlers{}
+	h := &Handler{NewAccount: rec}
+
+	if w := postWebhook(h, newAccountXML, "", ""); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if rec.newAccountCalls != 1 {
+		t.Errorf("expected OnNewAccount to be called once, got %d", rec.newAccountCalls)
+	}
+}
+
+func TestHandlerDispatchesBillingInfoUpdated(t *testing.T) {
+	rec := &recordingHandlers{}
+	h := &Handler{BillingInfoUpdated: rec}
+
+	if w := postWebhook(h, billingInfoUpdatedXML, "", ""); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if rec.billingInfoUpdatedCalls != 1 {
+		t.Errorf("expected OnBillingInfoUpdated to be called once, got %d", rec.billingInfoUpdatedCalls)
+	}
+	if rec.gotBilling == nil || rec.gotBilling.FirstName != "Verena" {
+		t.Errorf("billing info was not unmarshaled correctly, got %+v", rec.gotBilling)
+	}
+}
+
+func TestHandlerDispatchesOldStyleBillingInfoUpdated(t *testing.T) {
+	rec := &recordingHandlers{}
+	h := &Handler{BillingInfoUpdated: rec}
+
+	oldStyle := strings.NewReplacer(
+		"billing_info_updated_notification", "updated_billing_info_notification",
+	).Replace(billingInfoUpdatedXML)
+
+	if w := postWebhook(h, oldStyle, "", ""); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if rec.billingInfoUpdatedCalls != 1 {
+		t.Errorf("expected OnBillingInfoUpdated to be called once, got %d", rec.billingInfoUpdatedCalls)
+	}
+}
+
+func TestHandlerDispatchesExpiredSubscription(t *testing.T) {
+	rec := &recordingHandlers{}
+	h := &Handler{ExpiredSubscription: rec}
+
+	if w := postWebhook(h, expiredSubscriptionXML, "", ""); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if rec.expiredSubscriptionCalls != 1 {
+		t.Errorf("expected OnExpiredSubscription to be called once, got %d", rec.expiredSubscriptionCalls)
+	}
+}
+
+func TestHandlerDispatchesSuccessfulPayment(t *testing.T) {
+	rec := &recordingHandlers{}
+	h := &Handler{SuccessfulPayment: rec}
+
+	if w := postWebhook(h, successfulPaymentXML, "", ""); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if rec.successfulPaymentCalls != 1 {
+		t.Errorf("expected OnSuccessfulPayment to be called once, got %d", rec.successfulPaymentCalls)
+	}
+}
+
+func TestHandlerDispatchesFailedPayment(t *testing.T) {
+	rec := &recordingHandlers{}
+	h := &Handler{FailedPayment: rec}
+
+	if w := postWebhook(h, failedPaymentXML, "", ""); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if rec.failedPaymentCalls != 1 {
+		t.Errorf("expected OnFailedPayment to be called once, got %d", rec.failedPaymentCalls)
+	}
+}
+
+func TestHandlerIgnoresUnrecognizedNotificationType(t *testing.T) {
+	h := &Handler{}
+
+	if w := postWebhook(h, `<some_other_notification></some_other_notification>`, "", ""); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestHandlerIgnoresNotificationWithNilHandler(t *testing.T) {
+	h := &Handler{}
+
+	if w := postWebhook(h, newAccountXML, "", ""); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}